@@ -36,6 +36,14 @@ type BlockDevice struct {
 	// BlockDevice if it exists
 	FSInfo FileSystemInformation
 
+	// PartitionInfo contains the partition table and partition entry
+	// details of this BlockDevice, if it is a partition
+	PartitionInfo PartitionInfo
+
+	// Encryption contains the encryption details of this BlockDevice,
+	// if it is encrypted
+	Encryption Encryption
+
 	// DeviceType is the type of the blockdevice. can be sparse/disk/partition etc
 	DeviceType string
 
@@ -82,6 +90,59 @@ type FileSystemInformation struct {
 
 	// MountPoint is the list of mountpoints at which this blockdevice is mounted
 	MountPoint []string
+
+	// UUID is the UUID of the filesystem present on the blockdevice, as
+	// assigned by mkfs. Unlike Path, it stays stable across reboots and
+	// device re-enumeration.
+	UUID string
+
+	// Label is the label of the filesystem present on the blockdevice,
+	// if one was set at mkfs time
+	Label string
+}
+
+// PartitionInfo contains the partition table and partition entry
+// details of a blockdevice that is itself a partition
+type PartitionInfo struct {
+	// TableType is the type of partition table present on the parent
+	// disk. eg: gpt, dos
+	TableType string
+
+	// TableUUID is the UUID of the partition table present on the
+	// parent disk
+	TableUUID string
+
+	// PartitionUUID is the UUID of this partition, as recorded in its
+	// partition table entry
+	PartitionUUID string
+
+	// PartitionType is the partition type GUID (gpt) or ID (dos) of
+	// this partition
+	PartitionType string
+
+	// PartitionNumber is the position of this partition in the
+	// partition table
+	PartitionNumber uint32
+}
+
+// Encryption contains the encryption details of a blockdevice, if it
+// is encrypted
+type Encryption struct {
+	// Type is the encryption mechanism used on this blockdevice. eg: luks
+	Type string
+
+	// LUKSVersion is the version of the LUKS header, valid only when
+	// Type is luks
+	LUKSVersion string
+
+	// UUID is the UUID of the encrypted volume
+	UUID string
+
+	// CipherName is the cipher used to encrypt this volume. eg: aes-xts-plain64
+	CipherName string
+
+	// KeySize is the size, in bits, of the encryption key
+	KeySize int
 }
 
 // Status is used to represent the status of the blockdevice
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockdevice
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// uuidPrefix is prepended to every BlockDevice UUID generated by NDM,
+// eg: blockdevice-xxx
+const uuidPrefix = "blockdevice-"
+
+// DeriveUUID returns the UUID that should be used to identify bd,
+// preferring identifiers that stay stable across a reboot or a
+// re-scan over a hash of path-derived attributes. Sources are tried in
+// this order:
+//
+//  1. wwn, the device's World Wide Name, when the hardware reports one
+//  2. bd.FSInfo.UUID, when bd carries a filesystem
+//  3. bd.PartitionInfo.PartitionUUID, when bd is a partition
+//  4. a hash of bd.Path and the node hostname, as before
+//
+// existingUUID, when non-empty, is the UUID already recorded for this
+// BlockDevice, eg: on its BlockDeviceClaim. It always wins over every
+// other source: a BlockDevice generated under the old hash-only scheme
+// must keep its UUID even once a stronger identifier becomes
+// available, so that its existing CR and any claim against it are not
+// orphaned.
+func DeriveUUID(bd *BlockDevice, wwn string, existingUUID string) string {
+	if existingUUID != "" {
+		return existingUUID
+	}
+
+	switch {
+	case wwn != "":
+		return uuidPrefix + hash(wwn)
+	case bd.FSInfo.UUID != "":
+		return uuidPrefix + hash(bd.FSInfo.UUID)
+	case bd.PartitionInfo.PartitionUUID != "":
+		return uuidPrefix + hash(bd.PartitionInfo.PartitionUUID)
+	default:
+		return uuidPrefix + hash(bd.Path, bd.NodeAttributes[HostName])
+	}
+}
+
+// hash returns the md5 hash of parts concatenated together, as a hex
+// string.
+func hash(parts ...string) string {
+	h := md5.New()
+	for _, part := range parts {
+		_, _ = h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockdevice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveUUIDExistingUUIDAlwaysWins(t *testing.T) {
+	bd := &BlockDevice{
+		Path:          "/dev/sda1",
+		FSInfo:        FileSystemInformation{UUID: "fs-uuid"},
+		PartitionInfo: PartitionInfo{PartitionUUID: "part-uuid"},
+	}
+
+	got := DeriveUUID(bd, "wwn-1", "blockdevice-existing")
+	assert.Equal(t, "blockdevice-existing", got)
+}
+
+func TestDeriveUUIDPrefersWWNOverFSAndPartitionUUID(t *testing.T) {
+	bd := &BlockDevice{
+		Path:          "/dev/sda1",
+		FSInfo:        FileSystemInformation{UUID: "fs-uuid"},
+		PartitionInfo: PartitionInfo{PartitionUUID: "part-uuid"},
+	}
+
+	got := DeriveUUID(bd, "wwn-1", "")
+	assert.Equal(t, uuidPrefix+hash("wwn-1"), got)
+}
+
+func TestDeriveUUIDPrefersFSUUIDOverPartitionUUID(t *testing.T) {
+	bd := &BlockDevice{
+		Path:          "/dev/sda1",
+		FSInfo:        FileSystemInformation{UUID: "fs-uuid"},
+		PartitionInfo: PartitionInfo{PartitionUUID: "part-uuid"},
+	}
+
+	got := DeriveUUID(bd, "", "")
+	assert.Equal(t, uuidPrefix+hash("fs-uuid"), got)
+}
+
+func TestDeriveUUIDFallsBackToPartitionUUID(t *testing.T) {
+	bd := &BlockDevice{
+		Path:          "/dev/sda1",
+		PartitionInfo: PartitionInfo{PartitionUUID: "part-uuid"},
+	}
+
+	got := DeriveUUID(bd, "", "")
+	assert.Equal(t, uuidPrefix+hash("part-uuid"), got)
+}
+
+func TestDeriveUUIDFallsBackToPathAndHostnameHash(t *testing.T) {
+	bd := &BlockDevice{
+		Path:           "/dev/sda1",
+		NodeAttributes: NodeAttribute{HostName: "node-1"},
+	}
+
+	got := DeriveUUID(bd, "", "")
+	assert.Equal(t, uuidPrefix+hash("/dev/sda1", "node-1"), got)
+}
+
+func TestHashIsStableAndOrderSensitive(t *testing.T) {
+	assert.Equal(t, hash("a", "b"), hash("a", "b"))
+	assert.NotEqual(t, hash("a", "b"), hash("b", "a"))
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Executor applies a MountTabDiff to the live kernel mount table using
+// the mount(2)/umount2(2) family of syscalls, so that libmount can be
+// used to reconcile a desired mount state rather than only observe
+// drift against one.
+type Executor struct{}
+
+// NewExecutor returns a new Executor.
+func NewExecutor() *Executor {
+	return &Executor{}
+}
+
+// FailedEntry pairs a MountTabDiffEntry that Apply could not carry out
+// with the error that caused it to fail.
+type FailedEntry struct {
+	Entry *MountTabDiffEntry
+	Err   error
+}
+
+// ApplyResult is the outcome of Apply. Applied holds the entries that
+// were carried out, in the order they were applied; Failed holds the
+// entries that were not, each with the error it failed with.
+type ApplyResult struct {
+	Applied MountTabDiff
+	Failed  []FailedEntry
+}
+
+// Apply performs the syscalls needed to bring the live mount table from
+// the old state of diff to its new state: mount(2) for
+// MountActionMount, umount2(2) for MountActionUmount, a move mount for
+// MountActionMove and MS_REMOUNT for MountActionRemount. Mount flags
+// (ro, nosuid, nodev, noexec, relatime, noatime, nodiratime) are
+// translated from Filesystem.GetVFSOptions(); anything Apply does not
+// recognise is passed through as filesystem-specific mount data.
+//
+// current is the mount table diff was generated against (GenerateDiff's
+// oldTab). Apply uses it to tell a genuinely new mount apart from a
+// bind mount: mountinfo never records "bind" as a VFS option, so a
+// MountActionMount whose source is already mounted elsewhere in
+// current is mounted with MS_BIND rather than re-mounting the
+// underlying device a second time. current may be nil if diff is known
+// not to contain any such bind mounts.
+//
+// Entries are applied in dependency order: unmounts are done
+// deepest-target-first so a parent is never unmounted while something
+// is still mounted under it, followed by moves, remounts and finally
+// mounts shallowest-target-first so a parent is always mounted before
+// anything under it.
+//
+// Apply is transactional per entry, not across the whole diff: a
+// failing entry is recorded in Failed and Apply moves on to the next
+// one rather than aborting. The entries that did succeed are returned
+// in Applied, ready to be passed to Rollback if the caller decides the
+// partial result is unusable.
+func (e *Executor) Apply(current *MountTab, diff MountTabDiff) *ApplyResult {
+	result := &ApplyResult{
+		Applied: NewMountTabDiff(),
+		Failed:  make([]FailedEntry, 0),
+	}
+
+	ordered := orderForApply(diff)
+	for _, entry := range ordered {
+		if err := e.applyEntry(entry, current); err != nil {
+			result.Failed = append(result.Failed, FailedEntry{Entry: entry, Err: err})
+			continue
+		}
+		result.Applied = result.Applied.AddDiffEntry(entry.oldFs, entry.newFs, entry.action)
+	}
+
+	return result
+}
+
+// Rollback inverts the entries of applied - typically the Applied
+// field of a partial ApplyResult - returning the live mount table to
+// the state it was in before Apply carried them out: a mount is
+// unmounted, an unmount is re-mounted, a move is moved back to its
+// prior target and a remount is reverted to its prior options. Entries
+// are inverted in the reverse of the order Apply used, respecting the
+// same parent/child dependency.
+func (e *Executor) Rollback(applied MountTabDiff) error {
+	for i := len(applied) - 1; i >= 0; i-- {
+		entry := applied[i]
+		if err := e.rollbackEntry(entry); err != nil {
+			return fmt.Errorf("rollback failed for %s: %v", targetOf(entry), err)
+		}
+	}
+	return nil
+}
+
+func (e *Executor) applyEntry(entry *MountTabDiffEntry, current *MountTab) error {
+	switch entry.action {
+	case MountActionMount:
+		return doMount(entry.newFs, current)
+	case MountActionUmount:
+		return unix.Unmount(entry.oldFs.GetTarget(), 0)
+	case MountActionMove:
+		return unix.Mount(entry.oldFs.GetTarget(), entry.newFs.GetTarget(), "", unix.MS_MOVE, "")
+	case MountActionRemount:
+		return doRemount(entry.newFs)
+	default:
+		return fmt.Errorf("unknown mount action %d", entry.action)
+	}
+}
+
+func (e *Executor) rollbackEntry(entry *MountTabDiffEntry) error {
+	switch entry.action {
+	case MountActionMount:
+		return unix.Unmount(entry.newFs.GetTarget(), 0)
+	case MountActionUmount:
+		// The original entry was already mounted in its own right
+		// before Apply ran, so there is no bind mount to detect here.
+		return doMount(entry.oldFs, nil)
+	case MountActionMove:
+		return unix.Mount(entry.newFs.GetTarget(), entry.oldFs.GetTarget(), "", unix.MS_MOVE, "")
+	case MountActionRemount:
+		return doRemount(entry.oldFs)
+	default:
+		return fmt.Errorf("unknown mount action %d", entry.action)
+	}
+}
+
+// doMount mounts fs. If current already has an entry for fs.GetSource()
+// - ie: the source is already mounted elsewhere - fs is bind mounted
+// onto its target instead of mounting the underlying device a second
+// time, since that is what a second mountinfo entry for an
+// already-mounted source actually means.
+func doMount(fs *Filesystem, current *MountTab) error {
+	flags, data := mountFlags(fs, current)
+	source := fs.GetSource()
+	if flags&unix.MS_BIND != 0 {
+		// A bind mount takes a path inside the already-mounted
+		// filesystem as its source, not the device node itself.
+		source = current.Find(SourceFilter(source)).GetTarget()
+	}
+	return unix.Mount(source, fs.GetTarget(), fs.GetFSType(), flags, data)
+}
+
+// mountFlags is the flag/data computation doMount performs, split out
+// so it can be tested without requiring the CAP_SYS_ADMIN a real
+// unix.Mount call needs.
+func mountFlags(fs *Filesystem, current *MountTab) (uintptr, string) {
+	flags, data := parseVFSOptions(fs.GetVFSOptions())
+	if current.Find(SourceFilter(fs.GetSource())) != nil {
+		flags |= unix.MS_BIND
+	}
+	return flags, data
+}
+
+func doRemount(fs *Filesystem) error {
+	flags, data := parseVFSOptions(fs.GetVFSOptions())
+	return unix.Mount(fs.GetSource(), fs.GetTarget(), fs.GetFSType(), flags|unix.MS_REMOUNT, data)
+}
+
+// orderForApply groups diff by action and sorts each group so that,
+// once concatenated as unmounts, moves, remounts, mounts, it can be
+// applied without a parent ever being acted on after its child.
+func orderForApply(diff MountTabDiff) MountTabDiff {
+	var umounts, moves, remounts, mounts MountTabDiff
+	for _, entry := range diff {
+		switch entry.action {
+		case MountActionUmount:
+			umounts = append(umounts, entry)
+		case MountActionMove:
+			moves = append(moves, entry)
+		case MountActionRemount:
+			remounts = append(remounts, entry)
+		case MountActionMount:
+			mounts = append(mounts, entry)
+		}
+	}
+
+	sort.SliceStable(umounts, func(i, j int) bool {
+		return targetDepth(umounts[i].oldFs.GetTarget()) > targetDepth(umounts[j].oldFs.GetTarget())
+	})
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return targetDepth(mounts[i].newFs.GetTarget()) < targetDepth(mounts[j].newFs.GetTarget())
+	})
+
+	ordered := NewMountTabDiff()
+	ordered = append(ordered, umounts...)
+	ordered = append(ordered, moves...)
+	ordered = append(ordered, remounts...)
+	ordered = append(ordered, mounts...)
+	return ordered
+}
+
+func targetDepth(target string) int {
+	trimmed := strings.Trim(target, "/")
+	if trimmed == "" {
+		return 0
+	}
+	return strings.Count(trimmed, "/") + 1
+}
+
+func targetOf(entry *MountTabDiffEntry) string {
+	if entry.newFs != nil {
+		return entry.newFs.GetTarget()
+	}
+	if entry.oldFs != nil {
+		return entry.oldFs.GetTarget()
+	}
+	return ""
+}
+
+// vfsOptionFlags maps the VFS option strings found in
+// Filesystem.GetVFSOptions() to the mount(2) flag each represents.
+//
+// bind/rbind and the shared/private/slave/unbindable propagation
+// keywords are deliberately not mapped here: mountinfo never records
+// them in the per-mount options field (field 6) - propagation is
+// recorded separately, in the optional fields Filesystem.GetPropagation
+// parses, and a bind mount is recognised by Apply comparing the
+// source against the mount table it was generated from, not by a
+// keyword in this field.
+var vfsOptionFlags = map[string]uintptr{
+	"ro":         unix.MS_RDONLY,
+	"nosuid":     unix.MS_NOSUID,
+	"nodev":      unix.MS_NODEV,
+	"noexec":     unix.MS_NOEXEC,
+	"relatime":   unix.MS_RELATIME,
+	"noatime":    unix.MS_NOATIME,
+	"nodiratime": unix.MS_NODIRATIME,
+}
+
+// parseVFSOptions translates a comma separated VFS options string, as
+// returned by Filesystem.GetVFSOptions, into the mount(2) flags it
+// represents and any remaining options that are not mount(2) flags,
+// joined back together as the filesystem-specific data argument.
+func parseVFSOptions(vfsOptions string) (uintptr, string) {
+	var flags uintptr
+	data := make([]string, 0)
+
+	for _, opt := range strings.Split(vfsOptions, ",") {
+		opt = strings.TrimSpace(opt)
+		switch {
+		case opt == "":
+			continue
+		case opt == "rw":
+			// rw is the default; MS_RDONLY is simply left unset.
+			continue
+		default:
+			if flag, ok := vfsOptionFlags[opt]; ok {
+				flags |= flag
+				continue
+			}
+			data = append(data, opt)
+		}
+	}
+
+	return flags, strings.Join(data, ",")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestOrderForApplyUnmountsDeepestFirstMountsShallowestFirst(t *testing.T) {
+	oldTab := parseTab(t, "36 35 98:0 / / rw master:1 - ext4 /dev/sda1 rw\n"+
+		"44 36 8:1 / /mnt rw shared:2 - ext4 /dev/sdb1 rw\n"+
+		"45 44 8:2 / /mnt/sub rw shared:3 - ext4 /dev/sdc1 rw\n")
+	newTab := parseTab(t, "36 35 98:0 / / rw master:1 - ext4 /dev/sda1 rw\n"+
+		"46 36 8:3 / /data rw shared:4 - ext4 /dev/sdd1 rw\n"+
+		"47 46 8:4 / /data/sub rw shared:5 - ext4 /dev/sde1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	ordered := orderForApply(diff)
+	require.Len(t, ordered, 4)
+
+	assert.Equal(t, MountActionUmount, ordered[0].GetAction())
+	assert.Equal(t, "/mnt/sub", ordered[0].GetOldFs().GetTarget())
+	assert.Equal(t, MountActionUmount, ordered[1].GetAction())
+	assert.Equal(t, "/mnt", ordered[1].GetOldFs().GetTarget())
+
+	assert.Equal(t, MountActionMount, ordered[2].GetAction())
+	assert.Equal(t, "/data", ordered[2].GetNewFs().GetTarget())
+	assert.Equal(t, MountActionMount, ordered[3].GetAction())
+	assert.Equal(t, "/data/sub", ordered[3].GetNewFs().GetTarget())
+}
+
+func TestParseVFSOptionsKnownFlags(t *testing.T) {
+	flags, data := parseVFSOptions("ro,nosuid,relatime")
+	assert.Equal(t, unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_RELATIME, int(flags))
+	assert.Empty(t, data)
+}
+
+func TestParseVFSOptionsRWIsDefault(t *testing.T) {
+	flags, _ := parseVFSOptions("rw,relatime")
+	assert.Equal(t, uintptr(0), flags&unix.MS_RDONLY)
+}
+
+func TestParseVFSOptionsPassesThroughUnknownOptions(t *testing.T) {
+	flags, data := parseVFSOptions("relatime,data=ordered,errors=remount-ro")
+	assert.Equal(t, unix.MS_RELATIME, int(flags))
+	assert.Equal(t, "data=ordered,errors=remount-ro", data)
+}
+
+func TestParseVFSOptionsPropagationKeywordsPassThroughAsData(t *testing.T) {
+	// mountinfo's per-mount options field never actually contains these
+	// - propagation is reported separately, via Filesystem.GetPropagation
+	// - but if it ever did, they must not be silently dropped.
+	flags, data := parseVFSOptions("rbind,rprivate")
+	assert.Equal(t, uintptr(0), flags)
+	assert.Equal(t, "rbind,rprivate", data)
+}
+
+func TestMountFlagsBindsAlreadyMountedSource(t *testing.T) {
+	// Mirrors TestGenerateDiffBindMountNewTarget: /dev/sdb1 is already
+	// mounted at /mnt1, and is now also mounted at /mnt1-bind. That must
+	// be applied as a bind mount, not a second independent mount of the
+	// device.
+	current := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:17 / /mnt1-bind rw,relatime shared:3 - ext4 /dev/sdb1 rw\n")
+	bindFs := newTab.Find(TargetFilter("/mnt1-bind"))
+	require.NotNil(t, bindFs)
+
+	flags, _ := mountFlags(bindFs, current)
+	assert.NotZero(t, flags&unix.MS_BIND)
+}
+
+func TestMountFlagsDoesNotBindAGenuinelyNewMount(t *testing.T) {
+	current := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdc1 rw\n")
+	newFs := newTab.Find(TargetFilter("/mnt2"))
+	require.NotNil(t, newFs)
+
+	flags, _ := mountFlags(newFs, current)
+	assert.Zero(t, flags&unix.MS_BIND)
+}
+
+func TestTargetDepth(t *testing.T) {
+	assert.Equal(t, 0, targetDepth("/"))
+	assert.Equal(t, 1, targetDepth("/mnt"))
+	assert.Equal(t, 2, targetDepth("/mnt/sub"))
+}
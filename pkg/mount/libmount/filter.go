@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import "strings"
+
+// PrefixFilter returns a Filter that matches filesystem entries whose
+// target mount point starts with prefix. It is typically used to limit
+// a Watcher to a subtree such as "/dev/".
+func PrefixFilter(prefix string) Filter {
+	return func(fs *Filesystem) bool {
+		return strings.HasPrefix(fs.GetTarget(), prefix)
+	}
+}
+
+// FSTypeFilter returns a Filter that matches filesystem entries whose
+// filesystem type is one of fsTypes.
+func FSTypeFilter(fsTypes ...string) Filter {
+	return func(fs *Filesystem) bool {
+		for _, fsType := range fsTypes {
+			if fs.GetFSType() == fsType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ParentIDFilter returns a Filter that matches filesystem entries whose
+// parent mount ID is id.
+func ParentIDFilter(id int) Filter {
+	return func(fs *Filesystem) bool {
+		return fs.GetParentID() == id
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixFilter(t *testing.T) {
+	tab := parseTab(t, baseMountinfo+"45 36 8:18 / /dev/pts rw,relatime shared:4 - devpts devpts rw\n")
+	filtered := tab.Filter(PrefixFilter("/mnt"))
+	assert.Equal(t, 1, filtered.Size())
+	assert.Equal(t, "/mnt1", filtered.entries[0].GetTarget())
+}
+
+func TestFSTypeFilter(t *testing.T) {
+	tab := parseTab(t, baseMountinfo+"45 36 0:33 / /proc rw,relatime shared:4 - proc proc rw\n")
+	filtered := tab.Filter(FSTypeFilter("ext4"))
+	assert.Equal(t, 2, filtered.Size())
+}
+
+func TestParentIDFilter(t *testing.T) {
+	tab := parseTab(t, baseMountinfo)
+	filtered := tab.Filter(ParentIDFilter(36))
+	require.Equal(t, 1, filtered.Size())
+	assert.Equal(t, "/mnt1", filtered.entries[0].GetTarget())
+}
+
+func TestFiltersCompose(t *testing.T) {
+	tab := parseTab(t, baseMountinfo)
+	fs := tab.Find(PrefixFilter("/mnt"), FSTypeFilter("ext4"))
+	require.NotNil(t, fs)
+	assert.Equal(t, "/mnt1", fs.GetTarget())
+
+	assert.Nil(t, tab.Find(PrefixFilter("/mnt"), FSTypeFilter("xfs")))
+}
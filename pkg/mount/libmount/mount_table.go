@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package libmount parses and diffs the Linux kernel mount table, as
+// exposed through /proc/<pid>/mountinfo.
+package libmount
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Filesystem represents a single mounted filesystem, as parsed from
+// one line of /proc/<pid>/mountinfo.
+type Filesystem struct {
+	id          int
+	parentID    int
+	source      string
+	target      string
+	fsType      string
+	vfsOptions  string
+	fsOptions   string
+	propagation Propagation
+}
+
+// Propagation describes how mount/unmount events on a filesystem
+// propagate to and from its mount peer group, as recorded in the
+// optional fields of its mountinfo line (see proc(5)).
+type Propagation int
+
+const (
+	// PropagationPrivate is the default: the mount shares no events
+	// with any other mount.
+	PropagationPrivate Propagation = iota
+	// PropagationShared mounts (marked "shared:X" in mountinfo) are
+	// members of a peer group; events propagate between all members.
+	PropagationShared
+	// PropagationSlave mounts (marked "master:X") receive propagated
+	// events from their shared peer group, but don't send events back.
+	PropagationSlave
+	// PropagationUnbindable mounts cannot be bind mounted.
+	PropagationUnbindable
+)
+
+// GetID returns the unique mount ID the kernel assigned to this
+// filesystem.
+func (fs *Filesystem) GetID() int {
+	return fs.id
+}
+
+// GetParentID returns the mount ID of the parent of this filesystem in
+// the mount tree (or its own ID, for the root of the tree).
+func (fs *Filesystem) GetParentID() int {
+	return fs.parentID
+}
+
+// GetSource returns the mount source, eg: /dev/sda1.
+func (fs *Filesystem) GetSource() string {
+	return fs.source
+}
+
+// GetTarget returns the mount point.
+func (fs *Filesystem) GetTarget() string {
+	return fs.target
+}
+
+// GetFSType returns the filesystem type, eg: ext4.
+func (fs *Filesystem) GetFSType() string {
+	return fs.fsType
+}
+
+// GetVFSOptions returns the per-mount options, eg: "rw,relatime".
+func (fs *Filesystem) GetVFSOptions() string {
+	return fs.vfsOptions
+}
+
+// GetFSOptions returns the per-superblock options, eg:
+// "rw,errors=continue".
+func (fs *Filesystem) GetFSOptions() string {
+	return fs.fsOptions
+}
+
+// GetPropagation returns the mount's propagation type, as recorded in
+// its mountinfo optional fields.
+func (fs *Filesystem) GetPropagation() Propagation {
+	return fs.propagation
+}
+
+// Filter is a predicate over a Filesystem entry, used to select a
+// subset of a MountTab's entries via MountTab.Find/MountTab.Filter.
+type Filter func(*Filesystem) bool
+
+// SourceFilter returns a Filter that matches entries with the given
+// mount source.
+func SourceFilter(source string) Filter {
+	return func(fs *Filesystem) bool {
+		return fs.GetSource() == source
+	}
+}
+
+// TargetFilter returns a Filter that matches entries with the given
+// mount target.
+func TargetFilter(target string) Filter {
+	return func(fs *Filesystem) bool {
+		return fs.GetTarget() == target
+	}
+}
+
+// MountTab is a parsed mount table: the set of filesystems mounted at
+// the point it was read.
+type MountTab struct {
+	entries []*Filesystem
+}
+
+// Size returns the number of entries in the mount table. A nil
+// MountTab has size 0.
+func (mt *MountTab) Size() int {
+	if mt == nil {
+		return 0
+	}
+	return len(mt.entries)
+}
+
+// Find returns the first entry of mt that matches every one of
+// filters, or nil if none does.
+func (mt *MountTab) Find(filters ...Filter) *Filesystem {
+	if mt == nil {
+		return nil
+	}
+	for _, fs := range mt.entries {
+		if matchesAll(fs, filters) {
+			return fs
+		}
+	}
+	return nil
+}
+
+// Filter returns a new MountTab holding only the entries of mt that
+// match every one of filters.
+func (mt *MountTab) Filter(filters ...Filter) *MountTab {
+	filtered := &MountTab{entries: make([]*Filesystem, 0)}
+	if mt == nil {
+		return filtered
+	}
+	for _, fs := range mt.entries {
+		if matchesAll(fs, filters) {
+			filtered.entries = append(filtered.entries, fs)
+		}
+	}
+	return filtered
+}
+
+func matchesAll(fs *Filesystem, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(fs) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseMountTab parses a mount table in /proc/<pid>/mountinfo format,
+// as documented in proc(5).
+func ParseMountTab(r io.Reader) (*MountTab, error) {
+	tab := &MountTab{entries: make([]*Filesystem, 0)}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fs, err := parseMountinfoLine(line)
+		if err != nil {
+			return nil, err
+		}
+		tab.entries = append(tab.entries, fs)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return tab, nil
+}
+
+// parseMountinfoLine parses a single mountinfo line, eg:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// Fields, in order: mount ID, parent ID, major:minor, root,
+// mount point, mount options, zero or more optional fields, a literal
+// "-" separator, filesystem type, mount source, super options.
+func parseMountinfoLine(line string) (*Filesystem, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 {
+		return nil, fmt.Errorf("malformed mountinfo line: %q", line)
+	}
+
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed mount ID in mountinfo line: %q", line)
+	}
+	parentID, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed parent ID in mountinfo line: %q", line)
+	}
+
+	sepIdx := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			sepIdx = i
+			break
+		}
+	}
+	if sepIdx == -1 || sepIdx+3 >= len(fields) {
+		return nil, fmt.Errorf("missing \"-\" separator in mountinfo line: %q", line)
+	}
+
+	return &Filesystem{
+		id:          id,
+		parentID:    parentID,
+		target:      fields[4],
+		vfsOptions:  fields[5],
+		fsType:      fields[sepIdx+1],
+		source:      fields[sepIdx+2],
+		fsOptions:   fields[sepIdx+3],
+		propagation: parsePropagation(fields[6:sepIdx]),
+	}, nil
+}
+
+// parsePropagation determines a mount's Propagation from its
+// mountinfo optional fields (the zero or more fields between the
+// mount options and the "-" separator, eg: "shared:2", "master:3",
+// "unbindable").
+func parsePropagation(optionalFields []string) Propagation {
+	propagation := PropagationPrivate
+	for _, field := range optionalFields {
+		switch {
+		case field == "unbindable":
+			return PropagationUnbindable
+		case strings.HasPrefix(field, "shared:"):
+			propagation = PropagationShared
+		case strings.HasPrefix(field, "master:") && propagation == PropagationPrivate:
+			propagation = PropagationSlave
+		}
+	}
+	return propagation
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTab(t *testing.T, mountinfo string) *MountTab {
+	t.Helper()
+	tab, err := ParseMountTab(strings.NewReader(mountinfo))
+	require.NoError(t, err)
+	return tab
+}
+
+const baseMountinfo = `36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw
+44 36 8:17 / /mnt1 rw,relatime shared:3 - ext4 /dev/sdb1 rw
+`
+
+func TestGenerateDiffNewMount(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdc1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	require.Len(t, diff, 1)
+	assert.Equal(t, MountActionMount, diff[0].GetAction())
+	assert.Equal(t, "/mnt2", diff[0].GetNewFs().GetTarget())
+}
+
+func TestGenerateDiffUmount(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	require.Len(t, diff, 1)
+	assert.Equal(t, MountActionUmount, diff[0].GetAction())
+	assert.Equal(t, "/mnt1", diff[0].GetOldFs().GetTarget())
+}
+
+func TestGenerateDiffRemount(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n"+
+		"44 36 8:17 / /mnt1 ro,relatime shared:3 - ext4 /dev/sdb1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	require.Len(t, diff, 1)
+	assert.Equal(t, MountActionRemount, diff[0].GetAction())
+	assert.Equal(t, "ro,relatime", diff[0].GetNewFs().GetVFSOptions())
+}
+
+func TestGenerateDiffMove(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n"+
+		"44 36 8:17 / /mnt2 rw,relatime shared:3 - ext4 /dev/sdb1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	require.Len(t, diff, 1)
+	assert.Equal(t, MountActionMove, diff[0].GetAction())
+	assert.Equal(t, "/mnt1", diff[0].GetOldFs().GetTarget())
+	assert.Equal(t, "/mnt2", diff[0].GetNewFs().GetTarget())
+}
+
+func TestGenerateDiffBindMountNewTarget(t *testing.T) {
+	// A bind mount adds a second target for a source that is already
+	// mounted elsewhere - this must show up as a new mount, not a move,
+	// since the original target is still present in the new table.
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:17 / /mnt1-bind rw,relatime shared:3 - ext4 /dev/sdb1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	require.Len(t, diff, 1)
+	assert.Equal(t, MountActionMount, diff[0].GetAction())
+	assert.Equal(t, "/mnt1-bind", diff[0].GetNewFs().GetTarget())
+}
+
+func TestGenerateDiffRapidSuccessiveChanges(t *testing.T) {
+	// Several independent changes landing between two reads of the
+	// mount table (eg: within a single poll cycle) must all show up in
+	// one diff.
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n"+ // unchanged
+		"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdc1 rw\n"+ // new mount
+		"46 36 8:19 / /mnt3 rw,relatime shared:5 - ext4 /dev/sdd1 rw\n") // new mount, /mnt1 gone
+
+	diff := GenerateDiff(oldTab, newTab)
+	assert.Len(t, diff, 3) // /mnt1 umount, /mnt2 mount, /mnt3 mount
+
+	actions := make(map[MountAction]int)
+	for _, entry := range diff {
+		actions[entry.GetAction()]++
+	}
+	assert.Equal(t, 1, actions[MountActionUmount])
+	assert.Equal(t, 2, actions[MountActionMount])
+}
+
+func TestGenerateDiffNilTabs(t *testing.T) {
+	assert.Empty(t, GenerateDiff(nil, nil))
+
+	newTab := parseTab(t, baseMountinfo)
+	diff := GenerateDiff(nil, newTab)
+	assert.Len(t, diff, 2)
+	for _, entry := range diff {
+		assert.Equal(t, MountActionMount, entry.GetAction())
+	}
+}
+
+func TestListSources(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdc1 rw\n")
+
+	diff := GenerateDiff(oldTab, newTab)
+	assert.Equal(t, []string{"/dev/sdc1"}, diff.ListSources())
+}
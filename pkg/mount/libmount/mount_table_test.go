@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleMountinfo = `36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw,errors=remount-ro
+43 36 0:33 / /proc rw,nosuid,nodev,noexec,relatime shared:2 - proc proc rw
+44 36 8:17 / /mnt1 rw,relatime shared:3 - ext4 /dev/sdb1 rw,data=ordered
+45 36 8:17 / /mnt2 rw,relatime shared:3 - ext4 /dev/sdb1 rw,data=ordered
+`
+
+func TestParseMountTab(t *testing.T) {
+	tab, err := ParseMountTab(strings.NewReader(sampleMountinfo))
+	require.NoError(t, err)
+	assert.Equal(t, 4, tab.Size())
+
+	root := tab.Find(TargetFilter("/"))
+	require.NotNil(t, root)
+	assert.Equal(t, 36, root.GetID())
+	assert.Equal(t, 35, root.GetParentID())
+	assert.Equal(t, "/dev/sda1", root.GetSource())
+	assert.Equal(t, "ext4", root.GetFSType())
+	assert.Equal(t, "rw,noatime", root.GetVFSOptions())
+	assert.Equal(t, "rw,errors=remount-ro", root.GetFSOptions())
+}
+
+func TestParseMountTabMalformedLine(t *testing.T) {
+	_, err := ParseMountTab(strings.NewReader("not a valid mountinfo line"))
+	assert.Error(t, err)
+}
+
+func TestMountTabFindRequiresAllFilters(t *testing.T) {
+	tab, err := ParseMountTab(strings.NewReader(sampleMountinfo))
+	require.NoError(t, err)
+
+	fs := tab.Find(SourceFilter("/dev/sdb1"), TargetFilter("/mnt2"))
+	require.NotNil(t, fs)
+	assert.Equal(t, "/mnt2", fs.GetTarget())
+
+	assert.Nil(t, tab.Find(SourceFilter("/dev/sdb1"), TargetFilter("/does-not-exist")))
+}
+
+func TestMountTabFilterBindMountSameSource(t *testing.T) {
+	tab, err := ParseMountTab(strings.NewReader(sampleMountinfo))
+	require.NoError(t, err)
+
+	bound := tab.Filter(SourceFilter("/dev/sdb1"))
+	assert.Equal(t, 2, bound.Size())
+}
+
+func TestParseMountTabPropagation(t *testing.T) {
+	tab, err := ParseMountTab(strings.NewReader(sampleMountinfo +
+		"46 36 8:18 / /mnt3 rw,relatime - ext4 /dev/sdc1 rw\n" +
+		"47 36 8:19 / /mnt4 rw,relatime unbindable - ext4 /dev/sdd1 rw\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, PropagationSlave, tab.Find(TargetFilter("/")).GetPropagation())
+	assert.Equal(t, PropagationShared, tab.Find(TargetFilter("/mnt1")).GetPropagation())
+	assert.Equal(t, PropagationPrivate, tab.Find(TargetFilter("/mnt3")).GetPropagation())
+	assert.Equal(t, PropagationUnbindable, tab.Find(TargetFilter("/mnt4")).GetPropagation())
+}
+
+func TestMountTabSizeOnNil(t *testing.T) {
+	var tab *MountTab
+	assert.Equal(t, 0, tab.Size())
+	assert.Nil(t, tab.Find(SourceFilter("/dev/sda1")))
+	assert.Equal(t, 0, tab.Filter(SourceFilter("/dev/sda1")).Size())
+}
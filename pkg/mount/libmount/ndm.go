@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"sort"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+// BlockDeviceStore is the subset of the NDM probe pipeline's device
+// cache that UpdateMountPoints needs: looking up the BlockDevice a
+// mount source belongs to, and republishing it once its FSInfo has
+// changed.
+type BlockDeviceStore interface {
+	// BlockDeviceForSource returns the BlockDevice whose device node is
+	// source (eg: /dev/sda1), and whether one was found.
+	BlockDeviceForSource(source string) (*blockdevice.BlockDevice, bool)
+
+	// PublishBlockDevice republishes bd after UpdateMountPoints has
+	// updated its FSInfo.
+	PublishBlockDevice(bd *blockdevice.BlockDevice)
+}
+
+// UpdateMountPoints consumes diffs from w's Events channel and, for
+// every source in a diff that maps to a BlockDevice in store, updates
+// that BlockDevice's FSInfo.MountPoint in place and republishes it
+// through store. This lets a mount/unmount/remount/move on a tracked
+// device update NDM's view of it without the full-rescan a probe would
+// otherwise need. It runs until w's Events channel is closed and is
+// meant to be started in its own goroutine by the NDM probe pipeline.
+func UpdateMountPoints(w *Watcher, store BlockDeviceStore) {
+	for diff := range w.Events() {
+		for _, source := range diff.ListSources() {
+			bd, ok := store.BlockDeviceForSource(source)
+			if !ok {
+				continue
+			}
+			bd.FSInfo.MountPoint = mountPointsForSource(diff, source, bd.FSInfo.MountPoint)
+			store.PublishBlockDevice(bd)
+		}
+	}
+}
+
+// mountPointsForSource applies every entry of diff affecting source to
+// current, returning the resulting set of mount points. Multiple
+// mounts of the same source (bind mounts) simply accumulate as
+// multiple targets.
+func mountPointsForSource(diff MountTabDiff, source string, current []string) []string {
+	mounts := make(map[string]struct{}, len(current))
+	for _, target := range current {
+		mounts[target] = struct{}{}
+	}
+
+	for _, entry := range diff {
+		switch entry.GetAction() {
+		case MountActionMount:
+			if fs := entry.GetNewFs(); fs != nil && fs.GetSource() == source {
+				mounts[fs.GetTarget()] = struct{}{}
+			}
+		case MountActionUmount:
+			if fs := entry.GetOldFs(); fs != nil && fs.GetSource() == source {
+				delete(mounts, fs.GetTarget())
+			}
+		case MountActionMove:
+			if oldFs := entry.GetOldFs(); oldFs != nil && oldFs.GetSource() == source {
+				delete(mounts, oldFs.GetTarget())
+			}
+			if newFs := entry.GetNewFs(); newFs != nil && newFs.GetSource() == source {
+				mounts[newFs.GetTarget()] = struct{}{}
+			}
+		case MountActionRemount:
+			// options changed, not the set of mount points
+		}
+	}
+
+	result := make([]string, 0, len(mounts))
+	for target := range mounts {
+		result = append(result, target)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBlockDeviceStore struct {
+	bySource  map[string]*blockdevice.BlockDevice
+	published []*blockdevice.BlockDevice
+}
+
+func (s *fakeBlockDeviceStore) BlockDeviceForSource(source string) (*blockdevice.BlockDevice, bool) {
+	bd, ok := s.bySource[source]
+	return bd, ok
+}
+
+func (s *fakeBlockDeviceStore) PublishBlockDevice(bd *blockdevice.BlockDevice) {
+	s.published = append(s.published, bd)
+}
+
+func TestMountPointsForSourceMount(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdb1 rw\n")
+	diff := GenerateDiff(oldTab, newTab)
+
+	result := mountPointsForSource(diff, "/dev/sdb1", []string{"/mnt1"})
+	assert.ElementsMatch(t, []string{"/mnt1", "/mnt2"}, result)
+}
+
+func TestMountPointsForSourceUmount(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n")
+	diff := GenerateDiff(oldTab, newTab)
+
+	result := mountPointsForSource(diff, "/dev/sdb1", []string{"/mnt1"})
+	assert.Empty(t, result)
+}
+
+func TestMountPointsForSourceMove(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, "36 35 98:0 / / rw,noatime master:1 - ext4 /dev/sda1 rw\n"+
+		"44 36 8:17 / /mnt-moved rw,relatime shared:3 - ext4 /dev/sdb1 rw\n")
+	diff := GenerateDiff(oldTab, newTab)
+
+	result := mountPointsForSource(diff, "/dev/sdb1", []string{"/mnt1"})
+	assert.Equal(t, []string{"/mnt-moved"}, result)
+}
+
+func TestUpdateMountPointsPublishesOnlyTrackedSources(t *testing.T) {
+	oldTab := parseTab(t, baseMountinfo)
+	newTab := parseTab(t, baseMountinfo+"45 36 8:18 / /mnt2 rw,relatime shared:4 - ext4 /dev/sdc1 rw\n"+
+		"46 36 8:19 / /mnt3 rw,relatime shared:5 - ext4 /dev/sdd1 rw\n") // /dev/sdd1 is not tracked
+
+	w := &Watcher{
+		current: oldTab,
+		events:  make(chan MountTabDiff),
+		errors:  make(chan error, 1),
+		stopCh:  make(chan struct{}),
+	}
+
+	tracked := &blockdevice.BlockDevice{Path: "/dev/sdc1"}
+	store := &fakeBlockDeviceStore{
+		bySource: map[string]*blockdevice.BlockDevice{
+			"/dev/sdc1": tracked,
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		UpdateMountPoints(w, store)
+		close(done)
+	}()
+
+	w.events <- GenerateDiff(oldTab, newTab)
+	close(w.events)
+	<-done
+
+	assert.Equal(t, []string{"/mnt2"}, tracked.FSInfo.MountPoint)
+	assert.Len(t, store.published, 1)
+	assert.True(t, tracked == store.published[0])
+}
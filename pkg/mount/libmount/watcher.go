@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// mountInfoPath is the mountinfo file for the current process. The
+// kernel raises POLLPRI on this file whenever the mount table of this
+// mount namespace changes, which lets Watcher avoid busy polling.
+const mountInfoPath = "/proc/self/mountinfo"
+
+// Watcher watches the kernel mount table for changes and delivers the
+// MountTabDiff between the previous and the current state on a
+// channel. Entries are filtered before diffing, so a Watcher created
+// with eg: FSTypeFilter("ext4", "xfs") only ever reports changes to
+// mounts of those filesystem types.
+type Watcher struct {
+	filters []Filter
+
+	mu      sync.Mutex
+	current *MountTab
+
+	events   chan MountTabDiff
+	errors   chan error
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	// stopR/stopW are a self-pipe: watch blocks in unix.Poll with an
+	// infinite timeout, so closing stopW is what actually wakes it (a
+	// closed write end delivers POLLHUP/POLLIN on stopR), rather than
+	// stopCh alone, which watch would only notice the next time
+	// mountinfo itself changed.
+	stopR, stopW int
+}
+
+// NewWatcher returns a new Watcher that reports changes to entries
+// matching every one of filters. A Watcher created with no filters
+// reports every change in the mount table.
+func NewWatcher(filters ...Filter) (*Watcher, error) {
+	tab, err := readMountTab(filters...)
+	if err != nil {
+		return nil, fmt.Errorf("could not read initial mount table: %v", err)
+	}
+
+	var stopFds [2]int
+	if err := unix.Pipe(stopFds[:]); err != nil {
+		return nil, fmt.Errorf("could not create stop pipe: %v", err)
+	}
+
+	return &Watcher{
+		filters: filters,
+		current: tab,
+		events:  make(chan MountTabDiff),
+		errors:  make(chan error, 1),
+		stopCh:  make(chan struct{}),
+		stopR:   stopFds[0],
+		stopW:   stopFds[1],
+	}, nil
+}
+
+// Events returns the channel on which this Watcher delivers a
+// MountTabDiff every time the filtered mount table changes.
+func (w *Watcher) Events() <-chan MountTabDiff {
+	return w.events
+}
+
+// Errors returns the channel on which poll/parse errors encountered by
+// the watch loop are reported. The watch loop terminates after
+// reporting a poll error; parse errors are not fatal and the loop
+// keeps watching.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Start opens mountInfoPath and begins watching it for changes in a
+// background goroutine. Call Stop to terminate the goroutine.
+func (w *Watcher) Start() error {
+	fd, err := unix.Open(mountInfoPath, unix.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", mountInfoPath, err)
+	}
+
+	go w.watch(fd)
+	return nil
+}
+
+// Stop terminates the goroutine started by Start and closes the
+// Events channel. unix.Poll(-1) only wakes up for activity on a polled
+// fd, so Stop closes the watch loop's self-pipe write end rather than
+// relying on stopCh alone - otherwise the loop would only notice it
+// had been asked to stop the next time the real mount table changed,
+// which may be never. Safe to call more than once, and safe to call
+// even if Start was never called.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+		unix.Close(w.stopW)
+	})
+}
+
+func (w *Watcher) watch(fd int) {
+	defer unix.Close(fd)
+	defer unix.Close(w.stopR)
+	defer close(w.events)
+
+	pollFds := []unix.PollFd{
+		{Fd: int32(fd), Events: unix.POLLPRI | unix.POLLERR},
+		{Fd: int32(w.stopR), Events: unix.POLLIN},
+	}
+	stopFd := &pollFds[1]
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		// POLLPRI fires once per mount table change, but several
+		// changes may have landed by the time we get around to
+		// re-reading mountinfo. Diffing against the last parsed state
+		// still captures all of them as a single MountTabDiff.
+		if _, err := unix.Poll(pollFds, -1); err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			w.reportError(fmt.Errorf("poll on %s failed: %v", mountInfoPath, err))
+			return
+		}
+
+		if stopFd.Revents != 0 {
+			return
+		}
+
+		newTab, err := readMountTab(w.filters...)
+		if err != nil {
+			w.reportError(fmt.Errorf("could not parse %s: %v", mountInfoPath, err))
+			continue
+		}
+
+		w.mu.Lock()
+		oldTab := w.current
+		w.current = newTab
+		w.mu.Unlock()
+
+		diff := GenerateDiff(oldTab, newTab)
+		if len(diff) == 0 {
+			continue
+		}
+
+		select {
+		case w.events <- diff:
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+func (w *Watcher) reportError(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
+
+// readMountTab parses mountInfoPath, keeping only the entries that
+// match every one of filters.
+func readMountTab(filters ...Filter) (*MountTab, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tab, err := ParseMountTab(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return tab, nil
+	}
+	return tab.Filter(filters...), nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libmount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherStopUnblocksWatchAndClosesEvents(t *testing.T) {
+	w, err := NewWatcher()
+	require.NoError(t, err)
+	require.NoError(t, w.Start())
+
+	w.Stop()
+
+	select {
+	case _, ok := <-w.Events():
+		require.False(t, ok, "Events channel should be closed once Stop returns")
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch loop did not terminate after Stop")
+	}
+}
+
+func TestWatcherStopIsIdempotent(t *testing.T) {
+	w, err := NewWatcher()
+	require.NoError(t, err)
+	require.NoError(t, w.Start())
+
+	w.Stop()
+	require.NotPanics(t, w.Stop)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Attach merges attrs, typically the result of Resolver.Resolve, into
+// bd.NodeAttributes, so every BlockDevice NDM emits carries the zone
+// and region this node resolved to, not just the hostname/nodename
+// NDM already records there.
+func Attach(bd *blockdevice.BlockDevice, attrs blockdevice.NodeAttribute) {
+	if bd.NodeAttributes == nil {
+		bd.NodeAttributes = blockdevice.NodeAttribute{}
+	}
+	for k, v := range attrs {
+		bd.NodeAttributes[k] = v
+	}
+}
+
+// MetricLabels returns attrs' zone/region as a Prometheus label set,
+// so the existing NDM metrics that are already labeled per BlockDevice
+// can be sliced by failure domain by merging this in, without having
+// to plumb a Resolver through every metric call site themselves.
+func MetricLabels(attrs blockdevice.NodeAttribute) prometheus.Labels {
+	return prometheus.Labels{
+		"zone":   attrs[blockdevice.ZoneName],
+		"region": attrs[blockdevice.RegionName],
+	}
+}
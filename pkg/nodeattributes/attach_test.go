@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachMergesWithoutDroppingExistingAttributes(t *testing.T) {
+	bd := &blockdevice.BlockDevice{
+		NodeAttributes: blockdevice.NodeAttribute{blockdevice.HostName: "node-1"},
+	}
+
+	Attach(bd, blockdevice.NodeAttribute{
+		blockdevice.ZoneName:   "zone-a",
+		blockdevice.RegionName: "region-1",
+	})
+
+	assert.Equal(t, "node-1", bd.NodeAttributes[blockdevice.HostName])
+	assert.Equal(t, "zone-a", bd.NodeAttributes[blockdevice.ZoneName])
+	assert.Equal(t, "region-1", bd.NodeAttributes[blockdevice.RegionName])
+}
+
+func TestAttachInitializesNilNodeAttributes(t *testing.T) {
+	bd := &blockdevice.BlockDevice{}
+	Attach(bd, blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"})
+	assert.Equal(t, "zone-a", bd.NodeAttributes[blockdevice.ZoneName])
+}
+
+func TestMetricLabels(t *testing.T) {
+	labels := MetricLabels(blockdevice.NodeAttribute{
+		blockdevice.ZoneName:   "zone-a",
+		blockdevice.RegionName: "region-1",
+	})
+	assert.Equal(t, "zone-a", labels["zone"])
+	assert.Equal(t, "region-1", labels["region"])
+}
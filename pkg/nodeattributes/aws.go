@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+const (
+	awsTokenURL    = "http://169.254.169.254/latest/api/token"
+	awsZoneURL     = "http://169.254.169.254/latest/meta-data/placement/availability-zone"
+	awsTokenTTLSec = "21600"
+)
+
+// AWSProvider detects the zone/region of a node running on an EC2
+// instance, using the token-based IMDSv2 metadata API.
+type AWSProvider struct {
+	client   *http.Client
+	tokenURL string
+	zoneURL  string
+}
+
+// NewAWSProvider returns an AWSProvider that talks to the instance
+// metadata service using client. A nil client uses http.DefaultClient.
+func NewAWSProvider(client *http.Client) *AWSProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &AWSProvider{client: client, tokenURL: awsTokenURL, zoneURL: awsZoneURL}
+}
+
+// Name implements Provider.
+func (a *AWSProvider) Name() string {
+	return "aws"
+}
+
+// Detect implements Provider by fetching an IMDSv2 token and using it
+// to read the instance's availability zone, deriving the region from
+// it.
+func (a *AWSProvider) Detect(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	token, err := a.fetchToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.zoneURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+
+	zone, err := a.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockdevice.NodeAttribute{
+		blockdevice.ZoneName:   zone,
+		blockdevice.RegionName: awsRegionFromZone(zone),
+	}, nil
+}
+
+func (a *AWSProvider) fetchToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, a.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", awsTokenTTLSec)
+	return a.do(req)
+}
+
+func (a *AWSProvider) do(req *http.Request) (string, error) {
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL)
+	}
+	return string(body), nil
+}
+
+// awsRegionFromZone derives an AWS region from its availability zone,
+// eg: us-east-1a -> us-east-1.
+func awsRegionFromZone(zone string) string {
+	if len(zone) == 0 {
+		return ""
+	}
+	return zone[:len(zone)-1]
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSProviderDerivesRegionFromZone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			_, _ = w.Write([]byte("token-1"))
+		case http.MethodGet:
+			assert.Equal(t, "token-1", r.Header.Get("X-aws-ec2-metadata-token"))
+			_, _ = w.Write([]byte("us-east-1a"))
+		}
+	}))
+	defer srv.Close()
+
+	a := NewAWSProvider(srv.Client())
+	a.tokenURL = srv.URL
+	a.zoneURL = srv.URL
+
+	attrs, err := a.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, "us-east-1", attrs[blockdevice.RegionName])
+}
+
+func TestAWSProviderPropagatesTokenFetchFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	a := NewAWSProvider(srv.Client())
+	a.tokenURL = srv.URL
+	a.zoneURL = srv.URL
+
+	_, err := a.Detect(context.Background())
+	assert.Error(t, err)
+}
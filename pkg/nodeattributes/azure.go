@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+const azureMetadataURL = "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01"
+
+// AzureProvider detects the zone/region of a node running on an Azure
+// VM, using the Azure Instance Metadata Service.
+type AzureProvider struct {
+	client      *http.Client
+	metadataURL string
+}
+
+// NewAzureProvider returns an AzureProvider that talks to the instance
+// metadata service using client. A nil client uses http.DefaultClient.
+func NewAzureProvider(client *http.Client) *AzureProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &AzureProvider{client: client, metadataURL: azureMetadataURL}
+}
+
+// Name implements Provider.
+func (a *AzureProvider) Name() string {
+	return "azure"
+}
+
+type azureComputeMetadata struct {
+	Location string `json:"location"`
+	Zone     string `json:"zone"`
+}
+
+// Detect implements Provider by reading the instance's compute
+// metadata. Azure VMs that were not created with zonal redundancy
+// report an empty zone, but still report a region; Detect returns
+// whichever of the two the metadata service gave it rather than
+// discarding the region along with a missing zone, and only falls back
+// to ErrNotApplicable when neither was returned.
+func (a *AzureProvider) Detect(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, a.metadataURL)
+	}
+
+	var meta azureComputeMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, err
+	}
+
+	attrs := blockdevice.NodeAttribute{}
+	if meta.Zone != "" {
+		attrs[blockdevice.ZoneName] = meta.Zone
+	}
+	if meta.Location != "" {
+		attrs[blockdevice.RegionName] = meta.Location
+	}
+	if len(attrs) == 0 {
+		return nil, ErrNotApplicable
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureProviderReturnsRegionWithoutZone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"location":"eastus","zone":""}`))
+	}))
+	defer srv.Close()
+
+	a := NewAzureProvider(srv.Client())
+	a.metadataURL = srv.URL
+
+	attrs, err := a.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "eastus", attrs[blockdevice.RegionName])
+	_, hasZone := attrs[blockdevice.ZoneName]
+	assert.False(t, hasZone)
+}
+
+func TestAzureProviderNotApplicableWhenNothingReturned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"location":"","zone":""}`))
+	}))
+	defer srv.Close()
+
+	a := NewAzureProvider(srv.Client())
+	a.metadataURL = srv.URL
+
+	_, err := a.Detect(context.Background())
+	assert.Equal(t, ErrNotApplicable, err)
+}
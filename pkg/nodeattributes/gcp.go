@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+const gcpZoneURL = "http://metadata.google.internal/computeMetadata/v1/instance/zone"
+
+// GCPProvider detects the zone/region of a node running on a GCE
+// instance, using the GCP metadata server.
+type GCPProvider struct {
+	client  *http.Client
+	zoneURL string
+}
+
+// NewGCPProvider returns a GCPProvider that talks to the metadata
+// server using client. A nil client uses http.DefaultClient.
+func NewGCPProvider(client *http.Client) *GCPProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GCPProvider{client: client, zoneURL: gcpZoneURL}
+}
+
+// Name implements Provider.
+func (g *GCPProvider) Name() string {
+	return "gcp"
+}
+
+// Detect implements Provider by reading the instance's zone, which the
+// metadata server returns as a path such as
+// "projects/123456789/zones/us-central1-a", deriving the region from
+// it.
+func (g *GCPProvider) Detect(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.zoneURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, g.zoneURL)
+	}
+
+	zone := lastPathElement(string(body))
+	return blockdevice.NodeAttribute{
+		blockdevice.ZoneName:   zone,
+		blockdevice.RegionName: gcpRegionFromZone(zone),
+	}, nil
+}
+
+// gcpRegionFromZone derives a GCP region from its zone, eg:
+// us-central1-a -> us-central1.
+func gcpRegionFromZone(zone string) string {
+	idx := strings.LastIndex(zone, "-")
+	if idx == -1 {
+		return zone
+	}
+	return zone[:idx]
+}
+
+func lastPathElement(path string) string {
+	parts := strings.Split(path, "/")
+	return parts[len(parts)-1]
+}
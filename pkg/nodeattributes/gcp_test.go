@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCPProviderParsesZonePathAndDerivesRegion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		_, _ = w.Write([]byte("projects/123456789/zones/us-central1-a"))
+	}))
+	defer srv.Close()
+
+	g := NewGCPProvider(srv.Client())
+	g.zoneURL = srv.URL
+
+	attrs, err := g.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "us-central1-a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, "us-central1", attrs[blockdevice.RegionName])
+}
+
+func TestGCPProviderPropagatesUnexpectedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	g := NewGCPProvider(srv.Client())
+	g.zoneURL = srv.URL
+
+	_, err := g.Detect(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	zoneLabel     = "topology.kubernetes.io/zone"
+	regionLabel   = "topology.kubernetes.io/region"
+	hostnameLabel = "kubernetes.io/hostname"
+
+	// NodeNameEnv is the environment variable NDM's pod spec exposes
+	// the node name on, via the downward API (fieldRef: spec.nodeName).
+	NodeNameEnv = "NODE_NAME"
+)
+
+// K8sProvider detects zone/region from the well-known topology labels
+// on this node's Node object. It is the fallback provider for clusters
+// not running on one of the cloud providers with a dedicated Provider.
+type K8sProvider struct {
+	client   kubernetes.Interface
+	nodeName string
+}
+
+// NewK8sProvider returns a K8sProvider that looks up client's Node
+// object for nodeName. nodeName is typically NodeNameFromEnv(), which
+// reads the node name NDM's pod spec populates via the downward API.
+func NewK8sProvider(client kubernetes.Interface, nodeName string) *K8sProvider {
+	return &K8sProvider{client: client, nodeName: nodeName}
+}
+
+// NodeNameFromEnv returns the node name exposed via NodeNameEnv, or
+// the empty string if it is not set.
+func NodeNameFromEnv() string {
+	return os.Getenv(NodeNameEnv)
+}
+
+// Name implements Provider.
+func (k *K8sProvider) Name() string {
+	return "k8s"
+}
+
+// Detect implements Provider by reading the topology.kubernetes.io/
+// zone, topology.kubernetes.io/region and kubernetes.io/hostname
+// labels off this node's Node object.
+//
+// client-go v0.17's typed CoreV1Interface predates context-aware
+// methods, so Detect goes through the untyped RESTClient and sets the
+// request's Context(ctx) directly - otherwise ctx would never actually
+// bound the call, and a hung API server would block past the
+// Provider's promised per-provider timeout.
+func (k *K8sProvider) Detect(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	if k.nodeName == "" {
+		return nil, ErrNotApplicable
+	}
+
+	node := &corev1.Node{}
+	err := k.client.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(k.nodeName).
+		VersionedParams(&metav1.GetOptions{}, scheme.ParameterCodec).
+		Context(ctx).
+		Do().
+		Into(node)
+	if err != nil {
+		return nil, fmt.Errorf("could not get node %s: %v", k.nodeName, err)
+	}
+
+	attrs := blockdevice.NodeAttribute{}
+	if zone, ok := node.Labels[zoneLabel]; ok {
+		attrs[blockdevice.ZoneName] = zone
+	}
+	if region, ok := node.Labels[regionLabel]; ok {
+		attrs[blockdevice.RegionName] = region
+	}
+	if hostname, ok := node.Labels[hostnameLabel]; ok {
+		attrs[blockdevice.HostName] = hostname
+	}
+	if len(attrs) == 0 {
+		return nil, ErrNotApplicable
+	}
+
+	return attrs, nil
+}
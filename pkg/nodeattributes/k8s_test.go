@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+// fakeKubernetesInterface implements kubernetes.Interface by embedding
+// it (nil, so every unimplemented method panics if called) and
+// overriding CoreV1 with a stub that serves RESTClient from a fake
+// HTTP round tripper - client-go's own typed fake clientset does not
+// support RESTClient() (k8s.go's whole point), so it can't be reused
+// here.
+type fakeKubernetesInterface struct {
+	kubernetes.Interface
+	corev1 corev1client.CoreV1Interface
+}
+
+func (f *fakeKubernetesInterface) CoreV1() corev1client.CoreV1Interface {
+	return f.corev1
+}
+
+type fakeCoreV1 struct {
+	corev1client.CoreV1Interface
+	restClient *restfake.RESTClient
+}
+
+func (f *fakeCoreV1) RESTClient() rest.Interface {
+	return f.restClient
+}
+
+func newNodeGetClient(t *testing.T, node *corev1.Node, status int) kubernetes.Interface {
+	t.Helper()
+
+	roundTrip := func(req *http.Request) (*http.Response, error) {
+		body, err := json.Marshal(node)
+		require.NoError(t, err)
+		return &http.Response{
+			StatusCode: status,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+		}, nil
+	}
+
+	rc := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		GroupVersion:         corev1.SchemeGroupVersion,
+		Client:               restfake.CreateHTTPClient(roundTrip),
+	}
+
+	return &fakeKubernetesInterface{corev1: &fakeCoreV1{restClient: rc}}
+}
+
+func TestK8sProviderReadsZoneRegionAndHostnameLabels(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				zoneLabel:     "zone-a",
+				regionLabel:   "region-1",
+				hostnameLabel: "node-1",
+			},
+		},
+	}
+	client := newNodeGetClient(t, node, http.StatusOK)
+
+	k := NewK8sProvider(client, "node-1")
+	attrs, err := k.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, "region-1", attrs[blockdevice.RegionName])
+	assert.Equal(t, "node-1", attrs[blockdevice.HostName])
+}
+
+func TestK8sProviderNotApplicableWhenNoRelevantLabels(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	client := newNodeGetClient(t, node, http.StatusOK)
+
+	k := NewK8sProvider(client, "node-1")
+	_, err := k.Detect(context.Background())
+	assert.Equal(t, ErrNotApplicable, err)
+}
+
+func TestK8sProviderNotApplicableWhenNodeNameUnset(t *testing.T) {
+	k := NewK8sProvider(nil, "")
+	_, err := k.Detect(context.Background())
+	assert.Equal(t, ErrNotApplicable, err)
+}
+
+func TestNodeNameFromEnv(t *testing.T) {
+	require.NoError(t, os.Setenv(NodeNameEnv, "node-1"))
+	defer os.Unsetenv(NodeNameEnv)
+
+	assert.Equal(t, "node-1", NodeNameFromEnv())
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+// readOverrideFile reads a JSON-encoded blockdevice.NodeAttribute from
+// path, eg:
+//
+//	{"zone": "zone-a", "region": "region-1"}
+func readOverrideFile(path string) (blockdevice.NodeAttribute, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(blockdevice.NodeAttribute)
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
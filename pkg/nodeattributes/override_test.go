@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOverrideFileParsesZoneAndRegion(t *testing.T) {
+	path := writeOverrideFile(t, `{"zone": "zone-a", "region": "region-1"}`)
+
+	attrs, err := readOverrideFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, "region-1", attrs[blockdevice.RegionName])
+}
+
+func TestReadOverrideFileMissing(t *testing.T) {
+	_, err := readOverrideFile(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	assert.Error(t, err)
+}
+
+func TestReadOverrideFileMalformed(t *testing.T) {
+	path := writeOverrideFile(t, `not json`)
+
+	_, err := readOverrideFile(path)
+	assert.Error(t, err)
+}
+
+func writeOverrideFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "override.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), os.FileMode(0644)))
+	return path
+}
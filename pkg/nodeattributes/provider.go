@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodeattributes discovers the cloud zone/region of the node
+// NDM is running on, populating blockdevice.NodeAttribute's ZoneName
+// and RegionName keys.
+package nodeattributes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+// ErrNotApplicable is returned by a Provider when it can tell outright
+// that it does not apply to this node, eg: the Kubernetes provider
+// when no node name is set. Resolver treats it the same as any other Detect error:
+// it moves on to the next provider in priority order.
+var ErrNotApplicable = errors.New("nodeattributes: provider not applicable on this node")
+
+// Provider detects the zone and region of the node NDM is running on
+// from a single source, such as a cloud metadata service or the
+// Kubernetes API.
+type Provider interface {
+	// Name returns a short, human readable name for this provider, used
+	// in errors.
+	Name() string
+
+	// Detect returns the NodeAttribute entries this provider could
+	// determine. ctx bounds how long Detect may take; it must return
+	// promptly once ctx is done.
+	Detect(ctx context.Context) (blockdevice.NodeAttribute, error)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+// DefaultProviderTimeout is how long Resolver waits for a single
+// Provider before moving on to the next one in priority order.
+const DefaultProviderTimeout = 2 * time.Second
+
+// Resolver tries a list of Providers in priority order and caches the
+// outcome of the first resolution, result or error, for the lifetime
+// of the process, since a node's zone/region does not change while NDM
+// is running.
+type Resolver struct {
+	providers       []Provider
+	providerTimeout time.Duration
+	overrideFile    string
+
+	once   sync.Once
+	result blockdevice.NodeAttribute
+	err    error
+}
+
+// NewResolver returns a Resolver that tries providers in the given
+// order, giving each up to DefaultProviderTimeout to respond.
+// overrideFile, when non-empty, is checked before any provider is
+// tried; if it exists and parses, its contents are returned as-is,
+// letting air-gapped clusters configure zone/region without a
+// reachable cloud metadata service.
+func NewResolver(overrideFile string, providers ...Provider) *Resolver {
+	return &Resolver{
+		providers:       providers,
+		providerTimeout: DefaultProviderTimeout,
+		overrideFile:    overrideFile,
+	}
+}
+
+// Resolve returns the NodeAttribute detected for this node. The first
+// call tries the override file and then each provider in priority
+// order; its result, success or failure, is cached for the lifetime of
+// the process and returned directly by every later call.
+func (r *Resolver) Resolve(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	r.once.Do(func() {
+		r.result, r.err = r.resolve(ctx)
+	})
+	return r.result, r.err
+}
+
+func (r *Resolver) resolve(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	if r.overrideFile != "" {
+		if attrs, err := readOverrideFile(r.overrideFile); err == nil {
+			return attrs, nil
+		}
+	}
+
+	var lastErr error
+	for _, p := range r.providers {
+		pctx, cancel := context.WithTimeout(ctx, r.providerTimeout)
+		attrs, err := p.Detect(pctx)
+		cancel()
+		if err == nil {
+			return attrs, nil
+		}
+		lastErr = fmt.Errorf("%s: %v", p.Name(), err)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no nodeattributes providers configured")
+	}
+	return nil, fmt.Errorf("could not detect node zone/region: %v", lastErr)
+}
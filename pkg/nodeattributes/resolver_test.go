@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeattributes
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	name  string
+	attrs blockdevice.NodeAttribute
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Detect(ctx context.Context) (blockdevice.NodeAttribute, error) {
+	p.calls++
+	if p.delay > 0 {
+		select {
+		case <-time.After(p.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.attrs, p.err
+}
+
+func TestResolverTriesProvidersInPriorityOrderAndStopsOnFirstSuccess(t *testing.T) {
+	first := &fakeProvider{name: "first", err: ErrNotApplicable}
+	second := &fakeProvider{name: "second", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"}}
+	third := &fakeProvider{name: "third", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-b"}}
+
+	r := NewResolver("", first, second, third)
+	attrs, err := r.Resolve(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, 1, first.calls)
+	assert.Equal(t, 1, second.calls)
+	assert.Equal(t, 0, third.calls)
+}
+
+func TestResolverCachesResultAcrossCalls(t *testing.T) {
+	p := &fakeProvider{name: "only", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"}}
+
+	r := NewResolver("", p)
+	_, err := r.Resolve(context.Background())
+	require.NoError(t, err)
+	_, err = r.Resolve(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, p.calls)
+}
+
+func TestResolverReturnsErrorWhenEveryProviderFails(t *testing.T) {
+	first := &fakeProvider{name: "first", err: ErrNotApplicable}
+	second := &fakeProvider{name: "second", err: fmt.Errorf("boom")}
+
+	r := NewResolver("", first, second)
+	_, err := r.Resolve(context.Background())
+	assert.Error(t, err)
+}
+
+func TestResolverOverrideFileTakesPrecedenceOverProviders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "override.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"zone": "zone-override"}`), 0644))
+
+	p := &fakeProvider{name: "never-called", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"}}
+
+	r := NewResolver(path, p)
+	attrs, err := r.Resolve(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "zone-override", attrs[blockdevice.ZoneName])
+	assert.Equal(t, 0, p.calls)
+}
+
+func TestResolverFallsBackToProvidersWhenOverrideFileMissing(t *testing.T) {
+	p := &fakeProvider{name: "fallback", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"}}
+
+	r := NewResolver(filepath.Join(t.TempDir(), "does-not-exist.json"), p)
+	attrs, err := r.Resolve(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", attrs[blockdevice.ZoneName])
+	assert.Equal(t, 1, p.calls)
+}
+
+func TestResolverProviderTimeoutMovesOnToNextProvider(t *testing.T) {
+	slow := &fakeProvider{name: "slow", delay: 50 * time.Millisecond}
+	fast := &fakeProvider{name: "fast", attrs: blockdevice.NodeAttribute{blockdevice.ZoneName: "zone-a"}}
+
+	r := NewResolver("", slow, fast)
+	r.providerTimeout = 5 * time.Millisecond
+
+	attrs, err := r.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", attrs[blockdevice.ZoneName])
+}
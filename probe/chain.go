@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"fmt"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+)
+
+// Field identifies a single piece of BlockDevice information that a
+// Probe can fill in. Chain uses it to stop a later, weaker probe from
+// overwriting a field an earlier probe in the same Chain already
+// claimed.
+type Field string
+
+const (
+	// FieldFileSystem is owned by the probe that identifies
+	// BlockDevice.FSInfo.FileSystem
+	FieldFileSystem Field = "FileSystem"
+	// FieldFileSystemUUID is owned by the probe that identifies
+	// BlockDevice.FSInfo.UUID
+	FieldFileSystemUUID Field = "FileSystemUUID"
+	// FieldFileSystemLabel is owned by the probe that identifies
+	// BlockDevice.FSInfo.Label
+	FieldFileSystemLabel Field = "FileSystemLabel"
+	// FieldMountPoint is owned by the probe that identifies
+	// BlockDevice.FSInfo.MountPoint
+	FieldMountPoint Field = "MountPoint"
+	// FieldPartitionTable is owned by the probe that identifies
+	// BlockDevice.PartitionInfo.TableType/TableUUID
+	FieldPartitionTable Field = "PartitionTable"
+	// FieldPartitionEntry is owned by the probe that identifies the
+	// remaining BlockDevice.PartitionInfo fields
+	FieldPartitionEntry Field = "PartitionEntry"
+	// FieldEncryption is owned by the probe that identifies
+	// BlockDevice.Encryption
+	FieldEncryption Field = "Encryption"
+)
+
+// Probe identifies one stage of BlockDevice enrichment, eg: blkid,
+// partition table, LUKS header or mount table parsing.
+type Probe interface {
+	// Name returns a short, human readable name for this probe, used in
+	// errors.
+	Name() string
+
+	// Owns returns the Fields this probe authoritatively fills in.
+	// Chain skips Fill when every one of these Fields was already
+	// claimed by an earlier probe in the same Chain; otherwise it calls
+	// Fill and restores any already-claimed Field afterwards.
+	Owns() []Field
+
+	// Fill populates the Fields this probe owns on bd, based on the
+	// device at bd.Path. It must leave fields it does not own
+	// untouched.
+	Fill(bd *blockdevice.BlockDevice) error
+}
+
+// Chain runs an ordered list of Probes over a BlockDevice.
+type Chain struct {
+	probes []Probe
+}
+
+// NewChain returns a Chain that runs probes in the given order. The
+// recommended order is blkid, then partition, then luks, then mount,
+// so that each later probe can rely on the identity information
+// earlier probes in the chain already established.
+func NewChain(probes ...Probe) *Chain {
+	return &Chain{probes: probes}
+}
+
+// Run passes bd through every probe in the chain, in order. A probe
+// whose Owns() is entirely claimed by earlier probes is skipped
+// outright. A probe that only partially overlaps with earlier probes
+// is still run - Fill has no way to be told to populate just the
+// fields that are still unclaimed - but any field it shares with an
+// earlier, stronger probe is snapshotted beforehand and restored
+// afterwards, so Fill can never silently overwrite it.
+func (c *Chain) Run(bd *blockdevice.BlockDevice) error {
+	claimedBy := make(map[Field]string)
+
+	for _, p := range c.probes {
+		owned := p.Owns()
+
+		var restore []func()
+		for _, field := range owned {
+			if _, ok := claimedBy[field]; ok {
+				restore = append(restore, snapshotField(bd, field))
+			}
+		}
+		if len(restore) == len(owned) {
+			continue
+		}
+
+		if err := p.Fill(bd); err != nil {
+			return fmt.Errorf("probe %s failed for %s: %v", p.Name(), bd.Path, err)
+		}
+
+		for _, r := range restore {
+			r()
+		}
+		for _, field := range owned {
+			if _, ok := claimedBy[field]; !ok {
+				claimedBy[field] = p.Name()
+			}
+		}
+	}
+
+	return nil
+}
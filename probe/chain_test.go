@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/openebs/node-disk-manager/blockdevice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProbe struct {
+	name  string
+	owns  []Field
+	fill  func(bd *blockdevice.BlockDevice)
+	calls int
+}
+
+func (p *fakeProbe) Name() string  { return p.name }
+func (p *fakeProbe) Owns() []Field { return p.owns }
+func (p *fakeProbe) Fill(bd *blockdevice.BlockDevice) error {
+	p.calls++
+	p.fill(bd)
+	return nil
+}
+
+func TestChainRunsProbesInOrder(t *testing.T) {
+	blkid := &fakeProbe{
+		name: "blkid",
+		owns: []Field{FieldFileSystem, FieldFileSystemUUID},
+		fill: func(bd *blockdevice.BlockDevice) {
+			bd.FSInfo.FileSystem = "ext4"
+			bd.FSInfo.UUID = "blkid-uuid"
+		},
+	}
+	mount := &fakeProbe{
+		name: "mount",
+		owns: []Field{FieldMountPoint},
+		fill: func(bd *blockdevice.BlockDevice) {
+			bd.FSInfo.MountPoint = []string{"/data"}
+		},
+	}
+
+	bd := &blockdevice.BlockDevice{}
+	require.NoError(t, NewChain(blkid, mount).Run(bd))
+
+	assert.Equal(t, "ext4", bd.FSInfo.FileSystem)
+	assert.Equal(t, "blkid-uuid", bd.FSInfo.UUID)
+	assert.Equal(t, []string{"/data"}, bd.FSInfo.MountPoint)
+	assert.Equal(t, 1, blkid.calls)
+	assert.Equal(t, 1, mount.calls)
+}
+
+func TestChainSkipsProbeFullyClaimedByEarlierProbe(t *testing.T) {
+	blkid := &fakeProbe{
+		name: "blkid",
+		owns: []Field{FieldFileSystemUUID},
+		fill: func(bd *blockdevice.BlockDevice) { bd.FSInfo.UUID = "blkid-uuid" },
+	}
+	weaker := &fakeProbe{
+		name: "weaker",
+		owns: []Field{FieldFileSystemUUID},
+		fill: func(bd *blockdevice.BlockDevice) { bd.FSInfo.UUID = "weaker-uuid" },
+	}
+
+	bd := &blockdevice.BlockDevice{}
+	require.NoError(t, NewChain(blkid, weaker).Run(bd))
+
+	assert.Equal(t, "blkid-uuid", bd.FSInfo.UUID)
+	assert.Equal(t, 0, weaker.calls)
+}
+
+func TestChainProtectsPartiallyClaimedFieldFromOverwrite(t *testing.T) {
+	// blkid claims FieldFileSystemUUID. partition owns both
+	// FieldFileSystemUUID (which it would get wrong, since it is not
+	// blkid) and FieldPartitionEntry (which only it can fill). Its Fill
+	// cannot be told to only touch PartitionEntry, so the chain must
+	// restore FSInfo.UUID afterwards.
+	blkid := &fakeProbe{
+		name: "blkid",
+		owns: []Field{FieldFileSystemUUID},
+		fill: func(bd *blockdevice.BlockDevice) { bd.FSInfo.UUID = "blkid-uuid" },
+	}
+	partition := &fakeProbe{
+		name: "partition",
+		owns: []Field{FieldFileSystemUUID, FieldPartitionEntry},
+		fill: func(bd *blockdevice.BlockDevice) {
+			bd.FSInfo.UUID = "partition-uuid"
+			bd.PartitionInfo.PartitionUUID = "partition-entry-uuid"
+		},
+	}
+
+	bd := &blockdevice.BlockDevice{}
+	require.NoError(t, NewChain(blkid, partition).Run(bd))
+
+	assert.Equal(t, "blkid-uuid", bd.FSInfo.UUID)
+	assert.Equal(t, "partition-entry-uuid", bd.PartitionInfo.PartitionUUID)
+	assert.Equal(t, 1, partition.calls)
+}
+
+func TestChainPropagatesProbeError(t *testing.T) {
+	errProbe := &erroringProbe{name: "luks"}
+	err := NewChain(errProbe).Run(&blockdevice.BlockDevice{Path: "/dev/sda1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "luks")
+	assert.Contains(t, err.Error(), "/dev/sda1")
+}
+
+type erroringProbe struct{ name string }
+
+func (p *erroringProbe) Name() string  { return p.name }
+func (p *erroringProbe) Owns() []Field { return []Field{FieldEncryption} }
+func (p *erroringProbe) Fill(*blockdevice.BlockDevice) error {
+	return assert.AnError
+}
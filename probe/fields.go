@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The OpenEBS Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import "github.com/openebs/node-disk-manager/blockdevice"
+
+// snapshotField captures the current value of field on bd and returns
+// a function that, when called, writes that value back. Chain uses it
+// to protect a field a stronger probe already claimed from being
+// overwritten by a later probe that owns it alongside other,
+// still-unclaimed fields.
+func snapshotField(bd *blockdevice.BlockDevice, field Field) func() {
+	switch field {
+	case FieldFileSystem:
+		v := bd.FSInfo.FileSystem
+		return func() { bd.FSInfo.FileSystem = v }
+	case FieldFileSystemUUID:
+		v := bd.FSInfo.UUID
+		return func() { bd.FSInfo.UUID = v }
+	case FieldFileSystemLabel:
+		v := bd.FSInfo.Label
+		return func() { bd.FSInfo.Label = v }
+	case FieldMountPoint:
+		v := bd.FSInfo.MountPoint
+		return func() { bd.FSInfo.MountPoint = v }
+	case FieldPartitionTable:
+		tableType, tableUUID := bd.PartitionInfo.TableType, bd.PartitionInfo.TableUUID
+		return func() {
+			bd.PartitionInfo.TableType = tableType
+			bd.PartitionInfo.TableUUID = tableUUID
+		}
+	case FieldPartitionEntry:
+		uuid := bd.PartitionInfo.PartitionUUID
+		ptype := bd.PartitionInfo.PartitionType
+		number := bd.PartitionInfo.PartitionNumber
+		return func() {
+			bd.PartitionInfo.PartitionUUID = uuid
+			bd.PartitionInfo.PartitionType = ptype
+			bd.PartitionInfo.PartitionNumber = number
+		}
+	case FieldEncryption:
+		v := bd.Encryption
+		return func() { bd.Encryption = v }
+	default:
+		return func() {}
+	}
+}